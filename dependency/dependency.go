@@ -0,0 +1,55 @@
+// Package dependency provides the dependency.Manager interface that amboy
+// jobs use to describe their relationship to other jobs and to the
+// underlying resources they operate on.
+package dependency
+
+// TypeInfo identifies a dependency implementation, analogous to
+// amboy.JobType, and is used by the registry to reconstruct a
+// dependency.Manager from its interchange format.
+type TypeInfo struct {
+	Name    string `bson:"name" json:"name" yaml:"name"`
+	Version int    `bson:"version" json:"version" yaml:"version"`
+}
+
+// State describes whether a job's dependencies are satisfied.
+type State int
+
+// Valid State values.
+const (
+	Ready State = iota
+	Blocked
+	Passed
+)
+
+// Manager describes the dependency relationship between a job and other
+// jobs or resources. Queues consult a job's Manager to decide whether the
+// job is ready to run.
+type Manager interface {
+	Type() TypeInfo
+	State() State
+	Edges() []string
+	AddEdge(string) error
+}
+
+// alwaysDep is a no-op dependency.Manager that is always Ready.
+type alwaysDep struct {
+	DepType TypeInfo `bson:"type" json:"type" yaml:"type"`
+	EdgeSet []string `bson:"edges" json:"edges" yaml:"edges"`
+}
+
+// NewAlways returns a dependency.Manager that is always ready to run and has
+// no edges. It's the default dependency for jobs that don't need to
+// coordinate with other jobs.
+func NewAlways() Manager {
+	return &alwaysDep{
+		DepType: TypeInfo{Name: "always", Version: 0},
+	}
+}
+
+func (d *alwaysDep) Type() TypeInfo  { return d.DepType }
+func (d *alwaysDep) State() State    { return Ready }
+func (d *alwaysDep) Edges() []string { return d.EdgeSet }
+func (d *alwaysDep) AddEdge(e string) error {
+	d.EdgeSet = append(d.EdgeSet, e)
+	return nil
+}
@@ -0,0 +1,36 @@
+package amboy
+
+// Format represents the serialization format used by the registry when
+// converting jobs and dependencies to and from their interchange
+// representation.
+type Format int
+
+// Valid Format values. JSON and the BSON variants are used by queue
+// implementations that persist jobs to MongoDB or pass them over the wire.
+// Protobuf is intended for job types whose payloads are already proto
+// messages, where it avoids the cost of reflection-based encoding. The
+// registry has no YAML codec: payloads under YAML are encoded as JSON.
+const (
+	JSON Format = iota
+	BSON
+	BSON2
+	YAML
+	Protobuf
+)
+
+func (f Format) String() string {
+	switch f {
+	case JSON:
+		return "JSON"
+	case BSON:
+		return "BSON"
+	case BSON2:
+		return "BSON2"
+	case YAML:
+		return "YAML"
+	case Protobuf:
+		return "Protobuf"
+	default:
+		return "UNKNOWN"
+	}
+}
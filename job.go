@@ -0,0 +1,52 @@
+package amboy
+
+import (
+	"context"
+
+	"github.com/mongodb/amboy/dependency"
+)
+
+// JobType contains information about the type of a job, which is used by
+// the registry to reconstruct jobs from their serialized interchange format.
+type JobType struct {
+	Name    string `bson:"name" json:"name" yaml:"name"`
+	Version int    `bson:"version" json:"version" yaml:"version"`
+}
+
+// Job describes a single unit of work that amboy queues can dispatch,
+// execute, and track. Job implementations are responsible for their own
+// execution logic; amboy handles scheduling, retries, and serialization
+// around that logic.
+type Job interface {
+	// ID returns the unique identifier for the job.
+	ID() string
+	// Run executes the job's work.
+	Run(ctx context.Context)
+	// Type returns the registered type information for the job, which the
+	// registry uses to reconstruct the job from its interchange format.
+	Type() JobType
+
+	Dependency() dependency.Manager
+	SetDependency(dependency.Manager)
+
+	Error() error
+	AddError(error)
+
+	Status() JobStatusInfo
+	SetStatus(JobStatusInfo)
+
+	TimeInfo() JobTimeInfo
+	UpdateTimeInfo(JobTimeInfo)
+
+	RetryInfo() JobRetryInfo
+	UpdateRetryInfo(JobRetryInfoOptions)
+
+	Scopes() []string
+	SetScopes([]string)
+
+	EnqueueScopes() []string
+	SetEnqueueScopes(...string)
+
+	EnqueueAllScopes() bool
+	SetEnqueueAllScopes(bool)
+}
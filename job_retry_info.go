@@ -0,0 +1,30 @@
+package amboy
+
+// JobRetryInfo stores retry-related metadata for a job that supports being
+// retried after failure.
+type JobRetryInfo struct {
+	Retryable      bool `bson:"retryable" json:"retryable" yaml:"retryable"`
+	NeedsRetry     bool `bson:"needs_retry" json:"needs_retry" yaml:"needs_retry"`
+	CurrentAttempt int  `bson:"current_attempt" json:"current_attempt" yaml:"current_attempt"`
+	MaxAttempts    int  `bson:"max_attempts" json:"max_attempts" yaml:"max_attempts"`
+}
+
+// Options returns the mutable view of the retry info used by
+// Job.UpdateRetryInfo.
+func (i JobRetryInfo) Options() JobRetryInfoOptions {
+	return JobRetryInfoOptions{
+		Retryable:      &i.Retryable,
+		NeedsRetry:     &i.NeedsRetry,
+		CurrentAttempt: &i.CurrentAttempt,
+		MaxAttempts:    &i.MaxAttempts,
+	}
+}
+
+// JobRetryInfoOptions is a set of optional fields used to update a job's
+// JobRetryInfo in place; unset fields are left unmodified.
+type JobRetryInfoOptions struct {
+	Retryable      *bool
+	NeedsRetry     *bool
+	CurrentAttempt *int
+	MaxAttempts    *int
+}
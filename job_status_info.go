@@ -0,0 +1,52 @@
+package amboy
+
+import "time"
+
+// Error categories used to classify JobAttemptError.Category. These are
+// coarse buckets, not an exhaustive taxonomy -- they exist so that callers
+// triaging a failed job can tell "the job was cancelled" apart from
+// "the job itself returned an error" without parsing message text.
+const (
+	ErrorCategoryCancelled = "cancelled"
+	ErrorCategoryInternal  = "internal"
+	ErrorCategoryUnknown   = "unknown"
+)
+
+// JobAttemptError records a single error produced by one attempt of a job's
+// execution. Jobs that retry accumulate one of these per failed attempt,
+// which lets callers reconstruct what happened on each attempt rather than
+// only seeing a flattened list of messages.
+type JobAttemptError struct {
+	Attempt  int       `bson:"attempt" json:"attempt" yaml:"attempt"`
+	Time     time.Time `bson:"time" json:"time" yaml:"time"`
+	Category string    `bson:"category" json:"category" yaml:"category"`
+	Message  string    `bson:"message" json:"message" yaml:"message"`
+}
+
+// JobStatusInfo contains information about the current status of a job and
+// is used, in part, by the Queue interface to determine if a job is
+// complete or needs to run.
+type JobStatusInfo struct {
+	ID                string    `bson:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Owner             string    `bson:"owner" json:"owner" yaml:"owner"`
+	Completed         bool      `bson:"completed" json:"completed" yaml:"completed"`
+	InProgress        bool      `bson:"in_prog" json:"in_prog" yaml:"in_prog"`
+	Canceled          bool      `bson:"canceled" json:"canceled" yaml:"canceled"`
+	ModificationCount int       `bson:"mod_count" json:"mod_count" yaml:"mod_count"`
+	ModificationTime  time.Time `bson:"mod_ts" json:"mod_ts" yaml:"mod_ts"`
+	ErrorCount        int       `bson:"err_count" json:"err_count" yaml:"err_count"`
+
+	// Errors holds the flattened error messages for the job. It's kept
+	// populated alongside AttemptErrors for the benefit of callers that
+	// haven't migrated yet; it will be removed once downstream consumers
+	// have switched over to AttemptErrors.
+	//
+	// Deprecated: use AttemptErrors instead.
+	Errors []string `bson:"errs,omitempty" json:"errs,omitempty" yaml:"errs,omitempty"`
+
+	// AttemptErrors records the individual errors produced by each attempt
+	// of the job, keyed by JobRetryInfo.CurrentAttempt at the time of
+	// failure. Unlike Errors, this preserves which attempt produced which
+	// error and a coarse category for it.
+	AttemptErrors []JobAttemptError `bson:"attempt_errors,omitempty" json:"attempt_errors,omitempty" yaml:"attempt_errors,omitempty"`
+}
@@ -0,0 +1,18 @@
+package amboy
+
+import "time"
+
+// JobTimeInfo stores timing information for a job, and is used by queues to
+// determine dispatch order and by jobs to report how long they took to run.
+type JobTimeInfo struct {
+	Created    time.Time     `bson:"created,omitempty" json:"created,omitempty" yaml:"created,omitempty"`
+	Start      time.Time     `bson:"start,omitempty" json:"start,omitempty" yaml:"start,omitempty"`
+	End        time.Time     `bson:"end,omitempty" json:"end,omitempty" yaml:"end,omitempty"`
+	WaitUntil  time.Time     `bson:"wait_until,omitempty" json:"wait_until,omitempty" yaml:"wait_until,omitempty"`
+	DispatchBy time.Time     `bson:"dispatch_by,omitempty" json:"dispatch_by,omitempty" yaml:"dispatch_by,omitempty"`
+	MaxTime    time.Duration `bson:"max_time,omitempty" json:"max_time,omitempty" yaml:"max_time,omitempty"`
+}
+
+// Duration is a convenience function to return the duration of the job's
+// run, given the start and end time.
+func (j JobTimeInfo) Duration() time.Duration { return j.End.Sub(j.Start) }
@@ -0,0 +1,15 @@
+package amboy
+
+import "context"
+
+// Queue describes the subset of amboy's queue interface needed by code
+// that only submits jobs for execution, such as the scheduler in
+// registry/scheduler. A full queue implementation supports a much larger
+// surface (Get, Next, Results, Stats, Runner, and so on); callers that need
+// that should depend on a package that defines it.
+type Queue interface {
+	// Put adds a job to the queue, returning an error if the queue
+	// rejects it (for example, because a job with the same ID already
+	// exists).
+	Put(ctx context.Context, j Job) error
+}
@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Valid JobInterchange.Encoding values.
+const (
+	EncodingNone   = "none"
+	EncodingGzip   = "gzip"
+	EncodingZstd   = "zstd"
+	EncodingSnappy = "snappy"
+)
+
+var (
+	compressionCodec    = EncodingNone
+	compressionMinBytes = 0
+	compressionMutex    sync.RWMutex
+)
+
+// SetInterchangeCompression configures MakeJobInterchange to compress a
+// job's encoded payload with codec whenever it's at least minBytes long.
+// Jobs below the threshold are stored uncompressed, since gzip/zstd/snappy
+// framing overhead isn't worth paying on small payloads. The zero value
+// (EncodingNone, 0) is the default and leaves payloads untouched.
+//
+// This is a process-wide setting rather than a per-job option because it
+// governs how a queue encodes jobs on the way in, not how any one job
+// behaves; Resolve decompresses based on JobInterchange.Encoding, so
+// changing this setting never breaks previously-encoded payloads.
+func SetInterchangeCompression(codec string, minBytes int) {
+	compressionMutex.Lock()
+	defer compressionMutex.Unlock()
+
+	compressionCodec = codec
+	compressionMinBytes = minBytes
+}
+
+func currentCompressionSettings() (codec string, minBytes int) {
+	compressionMutex.RLock()
+	defer compressionMutex.RUnlock()
+
+	return compressionCodec, compressionMinBytes
+}
+
+// compressPayload applies the globally configured compression codec to raw
+// if it's long enough to be worth it, returning the encoding name that was
+// applied (EncodingNone if it wasn't).
+func compressPayload(raw []byte) (encoding string, out []byte, err error) {
+	codec, minBytes := currentCompressionSettings()
+	if codec == "" || codec == EncodingNone || len(raw) < minBytes {
+		return EncodingNone, raw, nil
+	}
+
+	out, err = compressWith(codec, raw)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "compressing payload with codec '%s'", codec)
+	}
+
+	return codec, out, nil
+}
+
+// decompressPayload reverses compressPayload using whatever encoding is
+// recorded on the JobInterchange, independent of the current global
+// compression setting -- a payload encoded under an old setting must still
+// decode correctly after the setting changes.
+func decompressPayload(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case "", EncodingNone:
+		return data, nil
+	case EncodingGzip, EncodingZstd, EncodingSnappy:
+		out, err := decompressWith(encoding, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decompressing payload with codec '%s'", encoding)
+		}
+		return out, nil
+	default:
+		return nil, errors.Errorf("unrecognized interchange encoding '%s'", encoding)
+	}
+}
+
+func compressWith(codec string, raw []byte) ([]byte, error) {
+	switch codec {
+	case EncodingGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(raw); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case EncodingZstd:
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer enc.Close()
+		return enc.EncodeAll(raw, nil), nil
+	case EncodingSnappy:
+		return snappy.Encode(nil, raw), nil
+	default:
+		return nil, errors.Errorf("unknown compression codec '%s'", codec)
+	}
+}
+
+func decompressWith(codec string, data []byte) ([]byte, error) {
+	switch codec {
+	case EncodingGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case EncodingZstd:
+		dec, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer dec.Close()
+		return dec.DecodeAll(data, nil)
+	case EncodingSnappy:
+		return snappy.Decode(nil, data)
+	default:
+		return nil, errors.Errorf("unknown compression codec '%s'", codec)
+	}
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/suite"
+)
+
+// CompressionSuite runs round-trip fidelity checks across the matrix of
+// interchange formats and compression codecs.
+type CompressionSuite struct {
+	job *JobTest
+	suite.Suite
+}
+
+func TestCompressionSuite(t *testing.T) {
+	suite.Run(t, new(CompressionSuite))
+}
+
+func (s *CompressionSuite) SetupTest() {
+	s.job = NewTestJob("compression-test")
+	s.job.Content = strings.Repeat("amboy job payload ", 200)
+}
+
+func (s *CompressionSuite) TearDownTest() {
+	SetInterchangeCompression(EncodingNone, 0)
+}
+
+func (s *CompressionSuite) TestRoundTripAcrossFormatAndEncodingMatrix() {
+	formats := []amboy.Format{amboy.JSON, amboy.BSON, amboy.BSON2}
+	codecs := []string{EncodingNone, EncodingGzip, EncodingZstd, EncodingSnappy}
+
+	for _, format := range formats {
+		for _, codec := range codecs {
+			SetInterchangeCompression(codec, 0)
+
+			i, err := MakeJobInterchange(s.job, format)
+			s.Require().NoError(err, "format %v codec %s", format, codec)
+			s.Equal(codec, i.Encoding, "format %v codec %s", format, codec)
+
+			out, err := i.Resolve(format)
+			s.Require().NoError(err, "format %v codec %s", format, codec)
+			s.Equal(s.job.Content, out.(*JobTest).Content, "format %v codec %s", format, codec)
+		}
+	}
+}
+
+func (s *CompressionSuite) TestPayloadsBelowThresholdAreNotCompressed() {
+	SetInterchangeCompression(EncodingGzip, 1<<20)
+
+	i, err := MakeJobInterchange(s.job, amboy.JSON)
+	s.Require().NoError(err)
+	s.Equal(EncodingNone, i.Encoding)
+}
+
+func (s *CompressionSuite) TestResolveDecompressesRegardlessOfCurrentSetting() {
+	SetInterchangeCompression(EncodingGzip, 0)
+	i, err := MakeJobInterchange(s.job, amboy.JSON)
+	s.Require().NoError(err)
+	s.Equal(EncodingGzip, i.Encoding)
+
+	SetInterchangeCompression(EncodingNone, 0)
+
+	out, err := i.Resolve(amboy.JSON)
+	s.Require().NoError(err)
+	s.Equal(s.job.Content, out.(*JobTest).Content)
+}
+
+// BenchmarkInterchangeSizeWithManyErrors compares the serialized size of a
+// job with a large error backlog across compression codecs, as a
+// size-regression guard for JobInterchange.Job. JobTest keeps its errors in
+// its status, which is carried in JobInterchange.Status rather than in the
+// Job payload, so the reported bytes don't include the error backlog.
+func BenchmarkInterchangeSizeWithManyErrors(b *testing.B) {
+	job := NewTestJob("bench-job")
+	for i := 0; i < 5000; i++ {
+		job.AddError(errLongBenchmarkError)
+	}
+
+	for _, codec := range []string{EncodingNone, EncodingGzip, EncodingZstd, EncodingSnappy} {
+		codec := codec
+		b.Run(codec, func(b *testing.B) {
+			SetInterchangeCompression(codec, 0)
+			defer SetInterchangeCompression(EncodingNone, 0)
+
+			b.ResetTimer()
+			var size int
+			for i := 0; i < b.N; i++ {
+				ji, err := MakeJobInterchange(job, amboy.BSON2)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(ji.Job)
+			}
+			b.ReportMetric(float64(size), "bytes/op")
+		})
+	}
+}
+
+var errLongBenchmarkError = benchmarkError(strings.Repeat("connection reset by peer while talking to upstream ", 20))
+
+type benchmarkError string
+
+func (e benchmarkError) Error() string { return string(e) }
@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/mongodb/amboy"
+)
+
+// InterchangeEventSink observes jobs as they cross the serialization
+// boundary. MakeJobInterchange and JobInterchange.Resolve are the natural
+// choke point for a queue's enqueue/dequeue path, so a sink registered here
+// sees every job that passes through either one, regardless of which queue
+// implementation is involved.
+type InterchangeEventSink interface {
+	// OnMake is called after a job has been successfully converted to its
+	// interchange form.
+	OnMake(ji *JobInterchange)
+	// OnResolve is called after an attempt to reconstruct a job from its
+	// interchange form, whether or not it succeeded; err is nil on success.
+	OnResolve(j amboy.Job, err error)
+}
+
+// eventQueueCapacity bounds how many pending events the dispatch loop will
+// buffer before new events are dropped. It exists so that a slow or stuck
+// sink can't apply backpressure to every MakeJobInterchange/Resolve call in
+// the process.
+const eventQueueCapacity = 1000
+
+type sinkEventKind int
+
+const (
+	sinkEventMake sinkEventKind = iota
+	sinkEventResolve
+)
+
+type sinkEvent struct {
+	kind sinkEventKind
+	ji   *JobInterchange
+	job  amboy.Job
+	err  error
+}
+
+var (
+	eventSinks      []InterchangeEventSink
+	eventSinksMutex sync.RWMutex
+	eventQueue      chan sinkEvent
+	eventLoopOnce   sync.Once
+	droppedEvents   uint64
+)
+
+// RegisterInterchangeEventSink adds sink to the set of sinks notified of
+// every MakeJobInterchange and JobInterchange.Resolve call. The first
+// registration starts a background dispatch goroutine that delivers events
+// to every registered sink in turn.
+func RegisterInterchangeEventSink(sink InterchangeEventSink) {
+	eventLoopOnce.Do(func() {
+		eventQueue = make(chan sinkEvent, eventQueueCapacity)
+		go dispatchInterchangeEvents()
+	})
+
+	eventSinksMutex.Lock()
+	defer eventSinksMutex.Unlock()
+
+	eventSinks = append(eventSinks, sink)
+}
+
+// DroppedInterchangeEventCount reports how many events have been dropped
+// because the dispatch queue was full, for callers that want to alert on a
+// sink falling behind.
+func DroppedInterchangeEventCount() uint64 {
+	return atomic.LoadUint64(&droppedEvents)
+}
+
+func dispatchInterchangeEvents() {
+	for ev := range eventQueue {
+		eventSinksMutex.RLock()
+		sinks := make([]InterchangeEventSink, len(eventSinks))
+		copy(sinks, eventSinks)
+		eventSinksMutex.RUnlock()
+
+		for _, sink := range sinks {
+			switch ev.kind {
+			case sinkEventMake:
+				sink.OnMake(ev.ji)
+			case sinkEventResolve:
+				sink.OnResolve(ev.job, ev.err)
+			}
+		}
+	}
+}
+
+// emitMake and emitResolve are non-blocking: if no sinks are registered
+// (the common case), they're a no-op, and if the dispatch queue is full,
+// the event is dropped and counted rather than blocking the caller.
+
+func emitMake(ji *JobInterchange) {
+	emitEvent(sinkEvent{kind: sinkEventMake, ji: ji})
+}
+
+func emitResolve(j amboy.Job, err error) {
+	emitEvent(sinkEvent{kind: sinkEventResolve, job: j, err: err})
+}
+
+func emitEvent(ev sinkEvent) {
+	eventSinksMutex.RLock()
+	hasSinks := len(eventSinks) > 0
+	eventSinksMutex.RUnlock()
+	if !hasSinks {
+		return
+	}
+
+	select {
+	case eventQueue <- ev:
+	default:
+		atomic.AddUint64(&droppedEvents, 1)
+	}
+}
@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/suite"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	made    []*JobInterchange
+	resolve []error
+}
+
+func (s *recordingSink) OnMake(ji *JobInterchange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.made = append(s.made, ji)
+}
+
+func (s *recordingSink) OnResolve(j amboy.Job, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolve = append(s.resolve, err)
+}
+
+func (s *recordingSink) madeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.made)
+}
+
+func (s *recordingSink) resolveCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.resolve)
+}
+
+type EventSinkSuite struct {
+	sink *recordingSink
+	suite.Suite
+}
+
+func TestEventSinkSuite(t *testing.T) {
+	suite.Run(t, new(EventSinkSuite))
+}
+
+func (s *EventSinkSuite) SetupTest() {
+	s.sink = &recordingSink{}
+	RegisterInterchangeEventSink(s.sink)
+}
+
+func (s *EventSinkSuite) TestMakeAndResolveNotifyRegisteredSink() {
+	job := NewTestJob("event-sink-test")
+
+	i, err := MakeJobInterchange(job, amboy.JSON)
+	s.Require().NoError(err)
+
+	_, err = i.Resolve(amboy.JSON)
+	s.Require().NoError(err)
+
+	s.Require().Eventually(func() bool {
+		return s.sink.madeCount() >= 1 && s.sink.resolveCount() >= 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (s *EventSinkSuite) TestResolveFailureStillNotifiesSinkWithError() {
+	job := NewTestJob("event-sink-test")
+	job.T.Name = "missing-job-type"
+
+	i, err := MakeJobInterchange(job, amboy.JSON)
+	s.Require().NoError(err)
+
+	before := s.sink.resolveCount()
+	_, err = i.Resolve(amboy.JSON)
+	s.Require().Error(err)
+
+	s.Require().Eventually(func() bool {
+		return s.sink.resolveCount() > before
+	}, time.Second, 5*time.Millisecond)
+
+	s.sink.mu.Lock()
+	defer s.sink.mu.Unlock()
+	s.Error(s.sink.resolve[len(s.sink.resolve)-1])
+}
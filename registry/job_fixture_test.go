@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+)
+
+// JobTest is a minimal amboy.Job implementation used to exercise the
+// interchange and registry machinery without depending on a real job
+// implementation.
+type JobTest struct {
+	Name       string            `bson:"name" json:"name" yaml:"name"`
+	Content    string            `bson:"content" json:"content" yaml:"content"`
+	ShouldFail bool              `bson:"should_fail" json:"should_fail" yaml:"should_fail"`
+	T          amboy.JobType     `bson:"type" json:"type" yaml:"type"`
+	TimingInfo amboy.JobTimeInfo `bson:"time_info" json:"time_info" yaml:"time_info"`
+
+	dep              dependency.Manager
+	status           amboy.JobStatusInfo
+	retryInfo        amboy.JobRetryInfo
+	scopes           []string
+	enqueueScopes    []string
+	enqueueAllScopes bool
+}
+
+func init() {
+	RegisterJobType("test", func() amboy.Job { return &JobTest{} })
+	RegisterDependencyType("always", func() dependency.Manager { return dependency.NewAlways() })
+}
+
+// NewTestJob returns a JobTest with the given name, registered under the
+// "test" job type.
+func NewTestJob(name string) *JobTest {
+	return &JobTest{
+		Name: name,
+		T:    amboy.JobType{Name: "test", Version: 0},
+		dep:  dependency.NewAlways(),
+	}
+}
+
+func (j *JobTest) ID() string { return j.Name }
+
+func (j *JobTest) Run(ctx context.Context) {
+	if j.ShouldFail {
+		j.AddError(errNotNil)
+	}
+}
+
+func (j *JobTest) Type() amboy.JobType { return j.T }
+
+func (j *JobTest) Dependency() dependency.Manager     { return j.dep }
+func (j *JobTest) SetDependency(d dependency.Manager) { j.dep = d }
+
+func (j *JobTest) Error() error {
+	if len(j.status.Errors) == 0 {
+		return nil
+	}
+	return errNotNil
+}
+
+func (j *JobTest) AddError(err error) {
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	j.status.ErrorCount++
+	j.status.Errors = append(j.status.Errors, msg)
+	j.status.AttemptErrors = append(j.status.AttemptErrors, amboy.JobAttemptError{
+		Attempt:  j.retryInfo.CurrentAttempt,
+		Time:     time.Now(),
+		Category: classifyError(msg),
+		Message:  msg,
+	})
+}
+
+func (j *JobTest) Status() amboy.JobStatusInfo     { return j.status }
+func (j *JobTest) SetStatus(s amboy.JobStatusInfo) { j.status = s }
+
+func (j *JobTest) TimeInfo() amboy.JobTimeInfo        { return j.TimingInfo }
+func (j *JobTest) UpdateTimeInfo(t amboy.JobTimeInfo) { j.TimingInfo = t }
+
+func (j *JobTest) RetryInfo() amboy.JobRetryInfo { return j.retryInfo }
+func (j *JobTest) UpdateRetryInfo(opts amboy.JobRetryInfoOptions) {
+	if opts.Retryable != nil {
+		j.retryInfo.Retryable = *opts.Retryable
+	}
+	if opts.NeedsRetry != nil {
+		j.retryInfo.NeedsRetry = *opts.NeedsRetry
+	}
+	if opts.CurrentAttempt != nil {
+		j.retryInfo.CurrentAttempt = *opts.CurrentAttempt
+	}
+	if opts.MaxAttempts != nil {
+		j.retryInfo.MaxAttempts = *opts.MaxAttempts
+	}
+}
+
+func (j *JobTest) Scopes() []string     { return j.scopes }
+func (j *JobTest) SetScopes(s []string) { j.scopes = s }
+
+func (j *JobTest) EnqueueScopes() []string      { return j.enqueueScopes }
+func (j *JobTest) SetEnqueueScopes(s ...string) { j.enqueueScopes = s }
+
+func (j *JobTest) EnqueueAllScopes() bool     { return j.enqueueAllScopes }
+func (j *JobTest) SetEnqueueAllScopes(v bool) { j.enqueueAllScopes = v }
+
+// errNotNil is a sentinel used by JobTest.Run and JobTest.Error, which don't
+// need to preserve a specific error beyond the fact that one occurred.
+type testError struct{}
+
+func (testError) Error() string { return "job failed" }
+
+var errNotNil = testError{}
@@ -0,0 +1,315 @@
+package registry
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxAttemptErrors and maxErrorMessageLength bound how much error data a
+// JobInterchange carries. Jobs that retry thousands of times against a
+// flaky dependency can otherwise accumulate an unbounded amount of error
+// text, which bloats the serialized form past what queue backends are
+// comfortable storing.
+const (
+	maxAttemptErrors      = 100
+	maxErrorMessageLength = 1024
+)
+
+// JobInterchange is a generic representation of a job used to convert to
+// and from the payload that is actually persisted or transmitted by queue
+// implementations. Job types register themselves with the registry so
+// that JobInterchange.Resolve can reconstruct a concrete amboy.Job.
+type JobInterchange struct {
+	Name             string                 `bson:"name" json:"name" yaml:"name"`
+	Type             string                 `bson:"type" json:"type" yaml:"type"`
+	Version          int                    `bson:"version" json:"version" yaml:"version"`
+	Status           amboy.JobStatusInfo    `bson:"status" json:"status" yaml:"status"`
+	RetryInfo        amboy.JobRetryInfo     `bson:"retry_info" json:"retry_info" yaml:"retry_info"`
+	TimeInfo         amboy.JobTimeInfo      `bson:"time_info" json:"time_info" yaml:"time_info"`
+	Job              []byte                 `bson:"job" json:"job" yaml:"job"`
+	Encoding         string                 `bson:"encoding,omitempty" json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Dependency       *DependencyInterchange `bson:"dependency" json:"dependency" yaml:"dependency"`
+	Scopes           []string               `bson:"scopes,omitempty" json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	EnqueueScopes    []string               `bson:"enqueue_scopes,omitempty" json:"enqueue_scopes,omitempty" yaml:"enqueue_scopes,omitempty"`
+	EnqueueAllScopes bool                   `bson:"enqueue_all_scopes" json:"enqueue_all_scopes" yaml:"enqueue_all_scopes"`
+}
+
+// DependencyInterchange is a generic representation of a dependency.Manager
+// used for converting to and from the payload that is actually persisted
+// for a job.
+type DependencyInterchange struct {
+	Type       string   `bson:"type" json:"type" yaml:"type"`
+	Version    int      `bson:"version" json:"version" yaml:"version"`
+	Edges      []string `bson:"edges" json:"edges" yaml:"edges"`
+	Dependency []byte   `bson:"dependency" json:"dependency" yaml:"dependency"`
+}
+
+// MakeJobInterchange converts a job into a JobInterchange structure using
+// the specified format for serializing the job itself. Dependency
+// information is always serialized using the same format.
+func MakeJobInterchange(j amboy.Job, f amboy.Format) (*JobInterchange, error) {
+	jobType := j.Type()
+
+	jobEncoded, err := encode(f, jobType.Name, j, GetJobProtoFactory)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding job")
+	}
+
+	encoding, jobEncoded, err := compressPayload(jobEncoded)
+	if err != nil {
+		return nil, err
+	}
+
+	dep, err := makeDependencyInterchange(f, j.Dependency())
+	if err != nil {
+		return nil, errors.Wrap(err, "converting dependency")
+	}
+
+	ji := &JobInterchange{
+		Name:             j.ID(),
+		Type:             jobType.Name,
+		Version:          jobType.Version,
+		Status:           truncateStatusErrors(j.Status()),
+		RetryInfo:        j.RetryInfo(),
+		TimeInfo:         j.TimeInfo(),
+		Job:              jobEncoded,
+		Encoding:         encoding,
+		Dependency:       dep,
+		Scopes:           j.Scopes(),
+		EnqueueScopes:    j.EnqueueScopes(),
+		EnqueueAllScopes: j.EnqueueAllScopes(),
+	}
+
+	emitMake(ji)
+
+	return ji, nil
+}
+
+// truncateStatusErrors bounds the amount of error data carried by a job's
+// status before it's persisted. It keeps the most recent errors -- both in
+// the legacy flat Errors slice and in the structured AttemptErrors slice --
+// and truncates each message to a reasonable length.
+//
+// Each AttemptErrors entry's Attempt is tagged by the job itself when the
+// error actually occurred (see amboy.Job.AddError implementations), not
+// re-derived here, so truncation alone can't mislabel an old error with
+// whatever attempt happens to be in progress when the job is serialized.
+func truncateStatusErrors(status amboy.JobStatusInfo) amboy.JobStatusInfo {
+	if len(status.Errors) == 0 && len(status.AttemptErrors) == 0 {
+		return status
+	}
+
+	truncated := status
+	truncated.Errors = truncateErrorMessages(status.Errors, maxAttemptErrors)
+	truncated.AttemptErrors = truncateAttemptErrors(status.AttemptErrors, maxAttemptErrors)
+
+	return truncated
+}
+
+// truncateAttemptErrors is the amboy.JobAttemptError equivalent of
+// truncateErrorMessages: it keeps the most recent limit entries, each with
+// its Message capped to maxErrorMessageLength.
+func truncateAttemptErrors(errs []amboy.JobAttemptError, limit int) []amboy.JobAttemptError {
+	start := 0
+	if len(errs) > limit {
+		start = len(errs) - limit
+	}
+
+	out := make([]amboy.JobAttemptError, 0, len(errs)-start)
+	for _, e := range errs[start:] {
+		if len(e.Message) > maxErrorMessageLength {
+			e.Message = e.Message[:maxErrorMessageLength]
+		}
+		out = append(out, e)
+	}
+
+	return out
+}
+
+// truncateErrorMessages keeps the most recent limit error messages (the
+// ones at the end of errs, which is append-ordered), each capped to
+// maxErrorMessageLength.
+func truncateErrorMessages(errs []string, limit int) []string {
+	start := 0
+	if len(errs) > limit {
+		start = len(errs) - limit
+	}
+
+	out := make([]string, 0, len(errs)-start)
+	for _, msg := range errs[start:] {
+		if len(msg) > maxErrorMessageLength {
+			msg = msg[:maxErrorMessageLength]
+		}
+		out = append(out, msg)
+	}
+
+	return out
+}
+
+// classifyError buckets an error message into a coarse category. It's a
+// best-effort heuristic based on common phrasing rather than a parsed error
+// type, since by the time an error reaches the interchange layer it's
+// already just text.
+func classifyError(msg string) string {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "context canceled"), strings.Contains(lower, "cancelled"), strings.Contains(lower, "canceled"):
+		return amboy.ErrorCategoryCancelled
+	case msg == "":
+		return amboy.ErrorCategoryUnknown
+	default:
+		return amboy.ErrorCategoryInternal
+	}
+}
+
+// Resolve reconstructs an amboy.Job from the JobInterchange payload using
+// the job type registered under i.Type, erroring if no such type is
+// registered. If the registered type's version differs from i.Version, the
+// payload is first passed through the chain of migrations registered with
+// RegisterJobMigration; Resolve errors only if no such chain exists or a
+// step in it fails.
+func (i *JobInterchange) Resolve(f amboy.Format) (j amboy.Job, err error) {
+	defer func() { emitResolve(j, err) }()
+
+	factory, err := GetJobFactory(i.Type)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving job named '%s'", i.Name)
+	}
+
+	j = factory()
+	raw, err := decompressPayload(i.Encoding, i.Job)
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing job payload")
+	}
+
+	if currentVersion := j.Type().Version; currentVersion != i.Version {
+		migrated, migrationErr := migrateJobPayload(i.Type, i.Version, currentVersion, raw, f)
+		if migrationErr != nil {
+			return nil, errors.Errorf("job '%s' has version %d, but interchange payload has version %d: %s",
+				i.Type, currentVersion, i.Version, migrationErr.Error())
+		}
+		raw = migrated
+	}
+
+	if err := decode(f, i.Type, raw, j, GetJobProtoFactory); err != nil {
+		return nil, errors.Wrap(err, "decoding job")
+	}
+
+	dep, err := convertToDependency(f, i.Dependency)
+	if err != nil {
+		return nil, errors.Wrap(err, "converting dependency")
+	}
+	j.SetDependency(dep)
+
+	j.SetStatus(i.Status)
+	j.UpdateTimeInfo(i.TimeInfo)
+	j.UpdateRetryInfo(i.RetryInfo.Options())
+	j.SetScopes(i.Scopes)
+	j.SetEnqueueScopes(i.EnqueueScopes...)
+	j.SetEnqueueAllScopes(i.EnqueueAllScopes)
+
+	return j, nil
+}
+
+// makeDependencyInterchange converts a dependency.Manager into its
+// interchange representation using the specified format.
+func makeDependencyInterchange(f amboy.Format, d dependency.Manager) (*DependencyInterchange, error) {
+	depEncoded, err := encodeDependency(f, d.Type().Name, d)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding dependency")
+	}
+
+	depType := d.Type()
+
+	return &DependencyInterchange{
+		Type:       depType.Name,
+		Version:    depType.Version,
+		Edges:      d.Edges(),
+		Dependency: depEncoded,
+	}, nil
+}
+
+// convertToDependency reconstructs a dependency.Manager from its
+// interchange representation using the registered dependency type.
+func convertToDependency(f amboy.Format, i *DependencyInterchange) (dependency.Manager, error) {
+	factory, err := GetDependencyFactory(i.Type)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving dependency type '%s'", i.Type)
+	}
+
+	d := factory()
+	raw := i.Dependency
+	if currentVersion := d.Type().Version; currentVersion != i.Version {
+		migrated, migrationErr := migrateDependencyPayload(i.Type, i.Version, currentVersion, raw, f)
+		if migrationErr != nil {
+			return nil, errors.Errorf("dependency '%s' has version %d, but interchange payload has version %d: %s",
+				i.Type, currentVersion, i.Version, migrationErr.Error())
+		}
+		raw = migrated
+	}
+
+	if err := decodeDependency(f, i.Type, raw, d); err != nil {
+		return nil, errors.Wrap(err, "decoding dependency")
+	}
+
+	return d, nil
+}
+
+// encodeDependency serializes a dependency manager using the codec selected
+// by f. Unlike encode, which is shared with jobs, the amboy.Protobuf case is
+// handled separately: jobs that don't opt into protobuf are a hard error,
+// but dependencies fall back to a JSON envelope so every dependency type
+// round-trips under every format, matching JSON/BSON/BSON2.
+func encodeDependency(f amboy.Format, typeName string, d dependency.Manager) ([]byte, error) {
+	if f == amboy.Protobuf {
+		return encodeDependencyProto(typeName, d)
+	}
+
+	return encode(f, typeName, d, dependencyProtoLookup)
+}
+
+// decodeDependency is the decode-side counterpart to encodeDependency.
+func decodeDependency(f amboy.Format, typeName string, data []byte, d dependency.Manager) error {
+	if f == amboy.Protobuf {
+		return decodeDependencyProto(typeName, data, dependencyProtoLookup, d)
+	}
+
+	return decode(f, typeName, data, d, dependencyProtoLookup)
+}
+
+// encode serializes v using the codec selected by f. typeName identifies
+// the registered job or dependency type that v belongs to, and lookup
+// finds its proto.Message factory, if any -- both are only consulted when
+// f is amboy.Protobuf.
+func encode(f amboy.Format, typeName string, v interface{}, lookup func(string) (func() proto.Message, bool)) ([]byte, error) {
+	switch f {
+	case amboy.Protobuf:
+		return encodeProto(typeName, v, lookup)
+	case amboy.BSON, amboy.BSON2:
+		return bson.Marshal(v)
+	default:
+		return json.Marshal(v)
+	}
+}
+
+// decode deserializes data into v using the codec selected by f.
+func decode(f amboy.Format, typeName string, data []byte, v interface{}, lookup func(string) (func() proto.Message, bool)) error {
+	switch f {
+	case amboy.Protobuf:
+		return decodeProto(typeName, data, lookup, func(msg proto.Message) {
+			if pv, ok := v.(proto.Message); ok {
+				proto.Merge(pv, msg)
+			}
+		})
+	case amboy.BSON, amboy.BSON2:
+		return bson.Unmarshal(data, v)
+	default:
+		return json.Unmarshal(data, v)
+	}
+}
@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// JobTestProto is a JobTest-equivalent fixture whose payload is a real
+// proto.Message: it embeds *structpb.Struct (a generated message shipped
+// by the protobuf runtime itself) and stores its fields in that Struct's
+// map, so it gets a genuine ProtoReflect()/Marshal/Unmarshal for free
+// without needing a type generated by protoc, which isn't available in
+// this environment. It exists to exercise the amboy.Protobuf format's
+// "payload implements proto.Message" path end to end.
+type JobTestProto struct {
+	*structpb.Struct
+
+	dep              dependency.Manager
+	status           amboy.JobStatusInfo
+	timingInfo       amboy.JobTimeInfo
+	retryInfo        amboy.JobRetryInfo
+	scopes           []string
+	enqueueScopes    []string
+	enqueueAllScopes bool
+}
+
+func init() {
+	RegisterJobType("test-proto", func() amboy.Job { return newEmptyJobTestProto() })
+	RegisterJobProtoFactory("test-proto", func() proto.Message { return newEmptyJobTestProto() })
+}
+
+func newEmptyJobTestProto() *JobTestProto {
+	return &JobTestProto{
+		Struct: &structpb.Struct{Fields: map[string]*structpb.Value{}},
+		dep:    dependency.NewAlways(),
+	}
+}
+
+// NewTestProtoJob returns a JobTestProto with the given name and content.
+func NewTestProtoJob(name, content string) *JobTestProto {
+	j := newEmptyJobTestProto()
+	j.setString("name", name)
+	j.setString("content", content)
+	return j
+}
+
+func (j *JobTestProto) setString(key, value string) {
+	j.Fields[key] = structpb.NewStringValue(value)
+}
+
+func (j *JobTestProto) getString(key string) string {
+	v, ok := j.Fields[key]
+	if !ok {
+		return ""
+	}
+	return v.GetStringValue()
+}
+
+func (j *JobTestProto) ID() string { return j.getString("name") }
+
+// Content returns the fixture's payload string, the proto-backed analog of
+// JobTest.Content.
+func (j *JobTestProto) Content() string { return j.getString("content") }
+
+func (j *JobTestProto) Run(ctx context.Context) {}
+
+func (j *JobTestProto) Type() amboy.JobType { return amboy.JobType{Name: "test-proto", Version: 0} }
+
+func (j *JobTestProto) Dependency() dependency.Manager     { return j.dep }
+func (j *JobTestProto) SetDependency(d dependency.Manager) { j.dep = d }
+
+func (j *JobTestProto) Error() error {
+	if len(j.status.Errors) == 0 {
+		return nil
+	}
+	return errNotNil
+}
+
+func (j *JobTestProto) AddError(err error) {
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	j.status.ErrorCount++
+	j.status.Errors = append(j.status.Errors, msg)
+	j.status.AttemptErrors = append(j.status.AttemptErrors, amboy.JobAttemptError{
+		Attempt:  j.retryInfo.CurrentAttempt,
+		Time:     time.Now(),
+		Category: classifyError(msg),
+		Message:  msg,
+	})
+}
+
+func (j *JobTestProto) Status() amboy.JobStatusInfo     { return j.status }
+func (j *JobTestProto) SetStatus(s amboy.JobStatusInfo) { j.status = s }
+
+func (j *JobTestProto) TimeInfo() amboy.JobTimeInfo        { return j.timingInfo }
+func (j *JobTestProto) UpdateTimeInfo(t amboy.JobTimeInfo) { j.timingInfo = t }
+
+func (j *JobTestProto) RetryInfo() amboy.JobRetryInfo { return j.retryInfo }
+func (j *JobTestProto) UpdateRetryInfo(opts amboy.JobRetryInfoOptions) {
+	if opts.Retryable != nil {
+		j.retryInfo.Retryable = *opts.Retryable
+	}
+	if opts.NeedsRetry != nil {
+		j.retryInfo.NeedsRetry = *opts.NeedsRetry
+	}
+	if opts.CurrentAttempt != nil {
+		j.retryInfo.CurrentAttempt = *opts.CurrentAttempt
+	}
+	if opts.MaxAttempts != nil {
+		j.retryInfo.MaxAttempts = *opts.MaxAttempts
+	}
+}
+
+func (j *JobTestProto) Scopes() []string     { return j.scopes }
+func (j *JobTestProto) SetScopes(s []string) { j.scopes = s }
+
+func (j *JobTestProto) EnqueueScopes() []string      { return j.enqueueScopes }
+func (j *JobTestProto) SetEnqueueScopes(s ...string) { j.enqueueScopes = s }
+
+func (j *JobTestProto) EnqueueAllScopes() bool     { return j.enqueueAllScopes }
+func (j *JobTestProto) SetEnqueueAllScopes(v bool) { j.enqueueAllScopes = v }
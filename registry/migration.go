@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"sync"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// JobMigrationFunc transforms the raw, still-encoded bytes of a job payload
+// from one version to the next. format tells the function which codec raw
+// is encoded with, since a migration may need to decode and re-encode the
+// payload to change its shape.
+type JobMigrationFunc func(raw []byte, format amboy.Format) ([]byte, error)
+
+// DependencyMigrationFunc is the dependency.Manager equivalent of
+// JobMigrationFunc.
+type DependencyMigrationFunc func(raw []byte, format amboy.Format) ([]byte, error)
+
+type migrationStep struct {
+	toVersion int
+	migrate   JobMigrationFunc
+}
+
+type dependencyMigrationStep struct {
+	toVersion int
+	migrate   DependencyMigrationFunc
+}
+
+var (
+	// jobMigrations is keyed by job type name, then by the version a step
+	// migrates *from*. Each step names the version it produces, so a chain
+	// from an old version to the current one can be walked one step at a
+	// time.
+	jobMigrations        = map[string]map[int]migrationStep{}
+	dependencyMigrations = map[string]map[int]dependencyMigrationStep{}
+	migrationMutex       sync.RWMutex
+)
+
+// RegisterJobMigration registers a step that upgrades a job type's
+// interchange payload from fromVersion to toVersion. Resolve walks a chain
+// of these, in order, to bring an old payload up to the currently
+// registered version before unmarshaling it, rather than rejecting it
+// outright on a version mismatch.
+func RegisterJobMigration(typeName string, fromVersion, toVersion int, fn JobMigrationFunc) {
+	migrationMutex.Lock()
+	defer migrationMutex.Unlock()
+
+	if jobMigrations[typeName] == nil {
+		jobMigrations[typeName] = map[int]migrationStep{}
+	}
+	jobMigrations[typeName][fromVersion] = migrationStep{toVersion: toVersion, migrate: fn}
+}
+
+// RegisterDependencyMigration is the dependency.Manager equivalent of
+// RegisterJobMigration.
+func RegisterDependencyMigration(typeName string, fromVersion, toVersion int, fn DependencyMigrationFunc) {
+	migrationMutex.Lock()
+	defer migrationMutex.Unlock()
+
+	if dependencyMigrations[typeName] == nil {
+		dependencyMigrations[typeName] = map[int]dependencyMigrationStep{}
+	}
+	dependencyMigrations[typeName][fromVersion] = dependencyMigrationStep{toVersion: toVersion, migrate: fn}
+}
+
+// migrateJobPayload walks the registered migration chain for typeName from
+// fromVersion to toVersion, applying each step's transformation to raw in
+// turn. It errors if no migration is registered for some version in the
+// chain, which leaves the caller to report the original version mismatch.
+func migrateJobPayload(typeName string, fromVersion, toVersion int, raw []byte, format amboy.Format) ([]byte, error) {
+	migrationMutex.RLock()
+	steps := jobMigrations[typeName]
+	migrationMutex.RUnlock()
+
+	version := fromVersion
+	for version != toVersion {
+		step, ok := steps[version]
+		if !ok {
+			return nil, errors.Errorf("no migration registered for job type '%s' from version %d", typeName, version)
+		}
+
+		migrated, err := step.migrate(raw, format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "migrating job type '%s' from version %d to %d", typeName, version, step.toVersion)
+		}
+
+		raw = migrated
+		version = step.toVersion
+	}
+
+	return raw, nil
+}
+
+// migrateDependencyPayload is the dependency.Manager equivalent of
+// migrateJobPayload.
+func migrateDependencyPayload(typeName string, fromVersion, toVersion int, raw []byte, format amboy.Format) ([]byte, error) {
+	migrationMutex.RLock()
+	steps := dependencyMigrations[typeName]
+	migrationMutex.RUnlock()
+
+	version := fromVersion
+	for version != toVersion {
+		step, ok := steps[version]
+		if !ok {
+			return nil, errors.Errorf("no migration registered for dependency type '%s' from version %d", typeName, version)
+		}
+
+		migrated, err := step.migrate(raw, format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "migrating dependency type '%s' from version %d to %d", typeName, version, step.toVersion)
+		}
+
+		raw = migrated
+		version = step.toVersion
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,92 @@
+// Package mqttevents provides a registry.InterchangeEventSink that
+// publishes job lifecycle events to an MQTT broker, borrowing the
+// broadcast-every-transition approach Flamenco Manager uses for its own
+// job events.
+package mqttevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+)
+
+// Client abstracts the subset of an MQTT client's API this package needs,
+// so that Sink can be exercised in tests without a real broker connection.
+type Client interface {
+	Publish(topic string, payload []byte) error
+}
+
+// Event is the compact JSON payload published for each job that crosses
+// the interchange boundary.
+type Event struct {
+	Queue     string    `json:"queue"`
+	JobType   string    `json:"job_type"`
+	JobID     string    `json:"job_id"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Sink is a registry.InterchangeEventSink that publishes an Event to the
+// topic "amboy/<queue>/<job-type>/<status>" for every job that's converted
+// to or reconstructed from its interchange form.
+type Sink struct {
+	client Client
+	queue  string
+}
+
+// NewSink returns a Sink that publishes through client under the given
+// queue name. JobInterchange doesn't carry the name of the queue it came
+// from, and every sink registered via registry.RegisterInterchangeEventSink
+// receives every event in the process, so queue is a fixed label: all
+// events this Sink publishes carry it, whichever queue the job came from.
+func NewSink(client Client, queue string) *Sink {
+	return &Sink{client: client, queue: queue}
+}
+
+// OnMake publishes a "made" event when a job is converted to its
+// interchange form.
+func (s *Sink) OnMake(ji *registry.JobInterchange) {
+	s.publish(ji.Type, ji.Name, "made", nil)
+}
+
+// OnResolve publishes a "resolved" event -- or, if resolution failed, a
+// "resolve-failed" event carrying the error -- when a job is reconstructed
+// from its interchange form.
+func (s *Sink) OnResolve(j amboy.Job, err error) {
+	status := "resolved"
+	jobType, jobID := "unknown", "unknown"
+	if j != nil {
+		jobType = j.Type().Name
+		jobID = j.ID()
+	}
+	if err != nil {
+		status = "resolve-failed"
+	}
+
+	s.publish(jobType, jobID, status, err)
+}
+
+func (s *Sink) publish(jobType, jobID, status string, jobErr error) {
+	event := Event{
+		Queue:     s.queue,
+		JobType:   jobType,
+		JobID:     jobID,
+		Status:    status,
+		Timestamp: time.Now(),
+	}
+	if jobErr != nil {
+		event.Error = jobErr.Error()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	topic := fmt.Sprintf("amboy/%s/%s/%s", s.queue, jobType, status)
+	_ = s.client.Publish(topic, payload)
+}
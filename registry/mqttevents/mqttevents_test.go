@@ -0,0 +1,70 @@
+package mqttevents
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeClient struct {
+	mu        sync.Mutex
+	published []publishCall
+}
+
+type publishCall struct {
+	topic   string
+	payload []byte
+}
+
+func (c *fakeClient) Publish(topic string, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.published = append(c.published, publishCall{topic: topic, payload: payload})
+	return nil
+}
+
+func (c *fakeClient) calls() []publishCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]publishCall, len(c.published))
+	copy(out, c.published)
+	return out
+}
+
+type SinkSuite struct {
+	client *fakeClient
+	sink   *Sink
+	suite.Suite
+}
+
+func TestSinkSuite(t *testing.T) {
+	suite.Run(t, new(SinkSuite))
+}
+
+func (s *SinkSuite) SetupTest() {
+	s.client = &fakeClient{}
+	s.sink = NewSink(s.client, "myqueue")
+}
+
+func (s *SinkSuite) TestOnMakePublishesToMadeTopic() {
+	s.sink.OnMake(&registry.JobInterchange{Name: "job-1", Type: "sometype"})
+
+	calls := s.client.calls()
+	s.Require().Len(calls, 1)
+	s.Equal("amboy/myqueue/sometype/made", calls[0].topic)
+	s.Contains(string(calls[0].payload), `"job_id":"job-1"`)
+}
+
+func (s *SinkSuite) TestOnResolveFailurePublishesResolveFailedWithError() {
+	s.sink.OnResolve(nil, errors.New("boom"))
+
+	calls := s.client.calls()
+	s.Require().Len(calls, 1)
+	s.Equal("amboy/myqueue/unknown/resolve-failed", calls[0].topic)
+	s.Contains(string(calls[0].payload), `"error":"boom"`)
+}
@@ -0,0 +1,25 @@
+package registry
+
+import (
+	"github.com/mongodb/amboy"
+	"google.golang.org/protobuf/proto"
+)
+
+// noProtoLookup is used by EncodeInterchangePayload/DecodeInterchangePayload,
+// which serialize arbitrary auxiliary records rather than registered job or
+// dependency types, so there's never a proto.Message factory to find.
+func noProtoLookup(string) (func() proto.Message, bool) { return nil, false }
+
+// EncodeInterchangePayload serializes v with the same codec
+// MakeJobInterchange uses for job and dependency payloads under format f.
+// It's exported for callers, such as the scheduler subpackage, that need to
+// persist their own auxiliary records through the same machinery rather
+// than inventing a second serialization path.
+func EncodeInterchangePayload(f amboy.Format, v interface{}) ([]byte, error) {
+	return encode(f, "", v, noProtoLookup)
+}
+
+// DecodeInterchangePayload reverses EncodeInterchangePayload.
+func DecodeInterchangePayload(f amboy.Format, data []byte, v interface{}) error {
+	return decode(f, "", data, v, noProtoLookup)
+}
@@ -0,0 +1,102 @@
+package registry
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// encodeProto marshals v as a protobuf message; v must itself implement
+// proto.Message, or an error is returned. lookup is either
+// GetJobProtoFactory or a dependency-flavored lookup with the same
+// signature, and is only consulted to pick the error message.
+func encodeProto(typeName string, v interface{}, lookup func(string) (func() proto.Message, bool)) ([]byte, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return proto.Marshal(msg)
+	}
+
+	if _, ok := lookup(typeName); ok {
+		// The registered factory only tells us how to construct a zero
+		// value for decoding; encoding still requires v itself to satisfy
+		// proto.Message, which it doesn't here.
+		return nil, errors.Errorf("type '%s' is registered for protobuf but its value does not implement proto.Message", typeName)
+	}
+
+	return nil, errors.Errorf("type '%s' does not support the protobuf format: register it with RegisterJobProtoFactory or implement proto.Message directly", typeName)
+}
+
+// decodeProto unmarshals data into a proto.Message constructed from the
+// factory registered for typeName, then copies it onto v via dst, which
+// should set whatever holds the decoded job or dependency.
+func decodeProto(typeName string, data []byte, lookup func(string) (func() proto.Message, bool), dst func(proto.Message)) error {
+	factory, ok := lookup(typeName)
+	if !ok {
+		return errors.Errorf("no protobuf factory registered for type '%s'", typeName)
+	}
+
+	msg := factory()
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return errors.Wrapf(err, "unmarshalling protobuf payload for type '%s'", typeName)
+	}
+
+	dst(msg)
+	return nil
+}
+
+// dependencyProtoLookup adapts the dependency proto registry to the
+// lookup signature shared with jobs. Dependency types don't currently have
+// a RegisterDependencyProtoFactory equivalent -- only job payloads are
+// expected to be large or hot enough to need protobuf today -- so this
+// always reports "unregistered", and encodeDependencyProto/
+// decodeDependencyProto fall back to a JSON envelope instead of failing.
+func dependencyProtoLookup(string) (func() proto.Message, bool) { return nil, false }
+
+// encodeDependencyProto marshals a dependency manager for the protobuf
+// format. Unlike encodeProto for jobs, this never hard-fails an
+// unregistered type: dependencies are small, queue-internal values rather
+// than the hot path protobuf targets jobs are, so a type that doesn't
+// implement proto.Message is instead JSON-encoded and wrapped in a
+// wrapperspb.BytesValue envelope, mirroring the "just works" behavior the
+// JSON/BSON/BSON2 paths already give every dependency type.
+func encodeDependencyProto(typeName string, v interface{}) ([]byte, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return proto.Marshal(msg)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "JSON-encoding dependency '%s' for protobuf envelope", typeName)
+	}
+
+	return proto.Marshal(wrapperspb.Bytes(raw))
+}
+
+// decodeDependencyProto reverses encodeDependencyProto: it unmarshals data
+// as whatever proto.Message factory is registered for typeName if one
+// exists, falling back to unwrapping the wrapperspb.BytesValue envelope and
+// JSON-decoding its contents into v.
+func decodeDependencyProto(typeName string, data []byte, lookup func(string) (func() proto.Message, bool), v interface{}) error {
+	if factory, ok := lookup(typeName); ok {
+		msg := factory()
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return errors.Wrapf(err, "unmarshalling protobuf payload for type '%s'", typeName)
+		}
+		if pv, ok := v.(proto.Message); ok {
+			proto.Merge(pv, msg)
+			return nil
+		}
+	}
+
+	var wrapper wrapperspb.BytesValue
+	if err := proto.Unmarshal(data, &wrapper); err != nil {
+		return errors.Wrapf(err, "unmarshalling protobuf envelope for type '%s'", typeName)
+	}
+
+	if err := json.Unmarshal(wrapper.GetValue(), v); err != nil {
+		return errors.Wrapf(err, "JSON-decoding protobuf envelope for type '%s'", typeName)
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtobufSuite covers the registry-level contract for the amboy.Protobuf
+// format: job types opt in by registering a proto.Message factory, and
+// anything that hasn't returns a clear error instead of silently falling
+// back to another codec.
+type ProtobufSuite struct {
+	job *JobTest
+	suite.Suite
+}
+
+func TestProtobufSuite(t *testing.T) {
+	suite.Run(t, new(ProtobufSuite))
+}
+
+func (s *ProtobufSuite) SetupTest() {
+	s.job = NewTestJob("protobuf-test")
+}
+
+func (s *ProtobufSuite) TestUnregisteredJobTypeErrorsRatherThanFallingBack() {
+	_, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Error(err)
+	s.Contains(err.Error(), "does not support the protobuf format")
+}
+
+func (s *ProtobufSuite) TestRegisteredFactoryIsReturnedByLookup() {
+	const typeName = "protobuf-suite-fixture"
+	factory := func() proto.Message { return nil }
+
+	RegisterJobProtoFactory(typeName, factory)
+
+	got, ok := GetJobProtoFactory(typeName)
+	s.True(ok)
+	s.NotNil(got)
+}
+
+func (s *ProtobufSuite) TestUnregisteredNameIsNotFound() {
+	_, ok := GetJobProtoFactory("no-such-protobuf-job-type")
+	s.False(ok)
+}
+
+// JobInterchangeProtobufSuite runs JobInterchangeSuite-equivalent round-trip
+// cases against JobTestProto, a fixture whose payload is a real proto.Message
+// (see job_proto_fixture_test.go), so that the amboy.Protobuf format gets
+// the same coverage as JSON/BSON/BSON2 rather than only the registry-level
+// contract above.
+type JobInterchangeProtobufSuite struct {
+	job *JobTestProto
+	suite.Suite
+}
+
+func TestJobInterchangeSuiteProtobuf(t *testing.T) {
+	suite.Run(t, new(JobInterchangeProtobufSuite))
+}
+
+func (s *JobInterchangeProtobufSuite) SetupTest() {
+	s.job = NewTestProtoJob("interchange-proto-test", "")
+}
+
+func (s *JobInterchangeProtobufSuite) TestRoundTripHighLevel() {
+	s.Equal("always", s.job.Dependency().Type().Name)
+
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Require().NoError(err)
+
+	outJob, err := i.Resolve(amboy.Protobuf)
+	s.Require().NoError(err)
+
+	new, ok := outJob.(*JobTestProto)
+	s.Require().True(ok)
+	s.Equal(s.job.ID(), new.ID())
+	s.Equal(s.job.Content(), new.Content())
+}
+
+func (s *JobInterchangeProtobufSuite) TestConversionToInterchangeMaintainsMetaDataFidelity() {
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	if s.NoError(err) {
+		s.Equal(s.job.ID(), i.Name)
+		s.Equal(s.job.Type().Name, i.Type)
+		s.Equal(s.job.Type().Version, i.Version)
+		s.Equal(s.job.Status(), i.Status)
+	}
+}
+
+func (s *JobInterchangeProtobufSuite) TestConversionToInterchangeTruncatesUnreasonablyLongErrorsInMetaData() {
+	const numErrs = 5000
+	for i := 0; i < numErrs; i++ {
+		s.job.AddError(errors.New(utility.MakeRandomString(10000)))
+	}
+
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.NoError(err)
+	s.Greater(numErrs, len(i.Status.Errors), "if a job has too many errors, it should truncate some of them down to a reasonable amount")
+}
+
+func (s *JobInterchangeProtobufSuite) TestConversionFromInterchangeMaintainsFidelity() {
+	s.job = NewTestProtoJob("interchange-proto-test", "some content")
+
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Require().NoError(err)
+
+	j, err := i.Resolve(amboy.Protobuf)
+	s.Require().NoError(err)
+	s.IsType(s.job, j)
+
+	new := j.(*JobTestProto)
+	s.Equal(s.job.ID(), new.ID())
+	s.Equal(s.job.Content(), new.Content())
+}
+
+func (s *JobInterchangeProtobufSuite) TestConvertToJobForUnknownJobType() {
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Require().NoError(err)
+
+	i.Type = "missing-proto-job-type"
+
+	j, err := i.Resolve(amboy.Protobuf)
+	s.Error(err)
+	s.Nil(j)
+}
+
+func (s *JobInterchangeProtobufSuite) TestMismatchedVersionResultsInErrorOnConversion() {
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Require().NoError(err)
+
+	i.Version += 100
+
+	j, err := i.Resolve(amboy.Protobuf)
+	s.Error(err)
+	s.Nil(j)
+}
+
+func (s *JobInterchangeProtobufSuite) TestTimeInfoPersists() {
+	now := time.Now()
+	ti := amboy.JobTimeInfo{
+		Start:     now.Round(time.Millisecond),
+		End:       now.Add(time.Hour).Round(time.Millisecond),
+		WaitUntil: now.Add(-time.Minute).Round(time.Millisecond),
+	}
+	s.job.UpdateTimeInfo(ti)
+
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Require().NoError(err)
+	s.Equal(ti, i.TimeInfo)
+
+	j, err := i.Resolve(amboy.Protobuf)
+	s.Require().NoError(err)
+	s.Equal(ti, j.TimeInfo())
+}
+
+func (s *JobInterchangeProtobufSuite) TestRetryInfoPersists() {
+	info := amboy.JobRetryInfo{Retryable: true, CurrentAttempt: 5}
+	s.job.UpdateRetryInfo(info.Options())
+	s.Equal(info, s.job.RetryInfo())
+
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Require().NoError(err)
+	s.Equal(info, i.RetryInfo)
+}
+
+func (s *JobInterchangeProtobufSuite) TestEnqueueScopesPersists() {
+	scopes := []string{"foo", "bar"}
+	s.job.SetScopes(scopes)
+	s.job.SetEnqueueScopes(scopes...)
+
+	i, err := MakeJobInterchange(s.job, amboy.Protobuf)
+	s.Require().NoError(err)
+
+	j, err := i.Resolve(amboy.Protobuf)
+	s.Require().NoError(err)
+	s.Equal(scopes, j.EnqueueScopes())
+}
+
+func BenchmarkEncodeJobBSON2(b *testing.B) {
+	job := NewTestJob("bench-job")
+	job.Content = benchmarkContent
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MakeJobInterchange(job, amboy.BSON2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEncodeJobProtobuf measures real protobuf encode performance, using JobTestProto's proto.Message-backed payload, so it's a
+// genuine comparison point against BenchmarkEncodeJobBSON2 rather than just
+// the error path taken for job types that haven't opted into amboy.Protobuf.
+func BenchmarkEncodeJobProtobuf(b *testing.B) {
+	job := NewTestProtoJob("bench-job", benchmarkContent)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MakeJobInterchange(job, amboy.Protobuf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkProtobufVsBSON2Size reports the encoded payload size under each
+// format on the same job content as custom benchmark metrics, so
+// `go test -bench` surfaces it alongside the speed benchmarks above.
+func BenchmarkProtobufVsBSON2Size(b *testing.B) {
+	protoJob := NewTestProtoJob("size-job", benchmarkContent)
+	bsonJob := NewTestJob("size-job")
+	bsonJob.Content = benchmarkContent
+
+	for i := 0; i < b.N; i++ {
+		protoInterchange, err := MakeJobInterchange(protoJob, amboy.Protobuf)
+		if err != nil {
+			b.Fatal(err)
+		}
+		bsonInterchange, err := MakeJobInterchange(bsonJob, amboy.BSON2)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(len(protoInterchange.Job)), "protobuf-bytes")
+		b.ReportMetric(float64(len(bsonInterchange.Job)), "bson2-bytes")
+	}
+}
+
+func BenchmarkEncodeJobProtobufUnsupported(b *testing.B) {
+	job := NewTestJob("bench-job")
+	job.Content = benchmarkContent
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MakeJobInterchange(job, amboy.Protobuf); err == nil {
+			b.Fatal("expected unsupported-format error")
+		}
+	}
+}
+
+const benchmarkContent = "the quick brown fox jumps over the lazy dog, repeated for bulk"
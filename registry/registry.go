@@ -0,0 +1,93 @@
+// Package registry provides a way for amboy to translate jobs and
+// dependency managers to and from a generic, serializable interchange
+// format. Queue implementations use this to pass job data between
+// processes without needing to know about concrete job types at compile
+// time; callers register their job and dependency types at package
+// initialization time via RegisterJobType and RegisterDependencyType.
+package registry
+
+import (
+	"sync"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	jobTypeRegistry        = map[string]func() amboy.Job{}
+	jobProtoRegistry       = map[string]func() proto.Message{}
+	dependencyTypeRegistry = map[string]func() dependency.Manager{}
+	registryMutex          sync.RWMutex
+)
+
+// RegisterJobType adds a factory for a named job type to the registry.
+// Job implementations should call this from an init function so that the
+// registry can reconstruct them from their interchange format.
+func RegisterJobType(name string, factory func() amboy.Job) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	jobTypeRegistry[name] = factory
+}
+
+// GetJobFactory returns the factory registered for the given job type name.
+func GetJobFactory(name string) (func() amboy.Job, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	factory, ok := jobTypeRegistry[name]
+	if !ok {
+		return nil, errors.Errorf("no job type named '%s' is registered", name)
+	}
+
+	return factory, nil
+}
+
+// RegisterJobProtoFactory registers a proto.Message factory for the named
+// job type alongside its RegisterJobType factory. When a queue serializes
+// the job using amboy.Protobuf, MakeJobInterchange marshals the job value,
+// which must itself implement proto.Message, and JobInterchange.Resolve
+// unmarshals the payload into a message built by this factory. There is no
+// fallback to another encoding under amboy.Protobuf. Job types that don't
+// register one can still be used with every other amboy.Format.
+func RegisterJobProtoFactory(name string, factory func() proto.Message) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	jobProtoRegistry[name] = factory
+}
+
+// GetJobProtoFactory returns the proto.Message factory registered for the
+// given job type name, if one was registered via RegisterJobProtoFactory.
+func GetJobProtoFactory(name string) (func() proto.Message, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	factory, ok := jobProtoRegistry[name]
+	return factory, ok
+}
+
+// RegisterDependencyType adds a factory for a named dependency.Manager
+// implementation to the registry.
+func RegisterDependencyType(name string, factory func() dependency.Manager) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	dependencyTypeRegistry[name] = factory
+}
+
+// GetDependencyFactory returns the factory registered for the given
+// dependency type name.
+func GetDependencyFactory(name string) (func() dependency.Manager, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	factory, ok := dependencyTypeRegistry[name]
+	if !ok {
+		return nil, errors.Errorf("no dependency type named '%s' is registered", name)
+	}
+
+	return factory, nil
+}
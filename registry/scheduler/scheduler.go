@@ -0,0 +1,204 @@
+// Package scheduler runs recurring jobs on a cron-like cadence, submitting
+// them to an amboy.Queue and persisting its own run-tracking state through
+// the same interchange machinery that jobs use, so it shares codecs with
+// the rest of amboy rather than inventing a second serialization path. The
+// persisted state is not compressed; SetInterchangeCompression only applies
+// to job payloads. It borrows the worker/scheduler split from the
+// Mattermost jobserver redesign: ScheduledJob describes what to run and
+// when, and Scheduler is the goroutine that decides when to actually run
+// it.
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+// ScheduledJob describes a single recurring job definition.
+type ScheduledJob struct {
+	// Name identifies this entry for logging and for its persisted
+	// SchedulerState; it must be unique within a Scheduler.
+	Name string
+	// Spec is a standard five-field cron expression.
+	Spec string
+	// Factory constructs a fresh job instance each time this entry fires.
+	Factory func() amboy.Job
+	// Enabled, if set, is consulted before each run; returning false skips
+	// that firing without advancing LastRun.
+	Enabled func() bool
+}
+
+// SchedulerState records the run-tracking state for one ScheduledJob
+// entry. It's persisted via registry.EncodeInterchangePayload/
+// DecodeInterchangePayload so that it shares codecs with the rest of the
+// interchange system.
+type SchedulerState struct {
+	Name    string    `bson:"name" json:"name" yaml:"name"`
+	LastRun time.Time `bson:"last_run" json:"last_run" yaml:"last_run"`
+	NextRun time.Time `bson:"next_run" json:"next_run" yaml:"next_run"`
+}
+
+// StateStore persists and retrieves a SchedulerState, already encoded,
+// under its entry name. Implementations might back this with a file,
+// MongoDB collection, or anything else; Scheduler doesn't care, as long as
+// LoadState returns what the most recent SaveState wrote.
+type StateStore interface {
+	SaveState(ctx context.Context, name string, encoded []byte) error
+	LoadState(ctx context.Context, name string) (encoded []byte, found bool, err error)
+}
+
+// LeaderElector decides whether this process is allowed to run scheduled
+// jobs right now. Scheduler calls IsLeader before every tick so that only
+// one process in a cluster submits a given entry's jobs.
+type LeaderElector interface {
+	IsLeader(ctx context.Context) bool
+}
+
+// NoopLeaderElector is the default LeaderElector for a single-node
+// deployment, where every process is always the leader.
+type NoopLeaderElector struct{}
+
+// IsLeader always returns true.
+func (NoopLeaderElector) IsLeader(ctx context.Context) bool { return true }
+
+type entry struct {
+	job      ScheduledJob
+	schedule cron.Schedule
+	state    SchedulerState
+}
+
+// Scheduler fires ScheduledJob entries on their configured cadence,
+// submitting the resulting job to queue.
+type Scheduler struct {
+	queue   amboy.Queue
+	store   StateStore
+	elector LeaderElector
+	format  amboy.Format
+	parser  cron.Parser
+
+	mu      sync.Mutex
+	entries []*entry
+
+	tickInterval time.Duration
+}
+
+// New returns a Scheduler that submits jobs to queue. store may be nil, in
+// which case run-tracking state isn't persisted across restarts. elector
+// may be nil, in which case NoopLeaderElector is used.
+func New(queue amboy.Queue, store StateStore, elector LeaderElector) *Scheduler {
+	if elector == nil {
+		elector = NoopLeaderElector{}
+	}
+
+	return &Scheduler{
+		queue:        queue,
+		store:        store,
+		elector:      elector,
+		format:       amboy.JSON,
+		parser:       cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow),
+		tickInterval: time.Minute,
+	}
+}
+
+// Register adds a ScheduledJob to the scheduler, loading any previously
+// persisted SchedulerState for it so that a restart doesn't lose track of
+// when it last ran.
+func (s *Scheduler) Register(ctx context.Context, job ScheduledJob) error {
+	schedule, err := s.parser.Parse(job.Spec)
+	if err != nil {
+		return errors.Wrapf(err, "parsing cron spec '%s' for job '%s'", job.Spec, job.Name)
+	}
+
+	state := SchedulerState{Name: job.Name, NextRun: schedule.Next(time.Now())}
+	if s.store != nil {
+		encoded, found, loadErr := s.store.LoadState(ctx, job.Name)
+		if loadErr != nil {
+			return errors.Wrapf(loadErr, "loading persisted state for job '%s'", job.Name)
+		}
+		if found {
+			if err := registry.DecodeInterchangePayload(s.format, encoded, &state); err != nil {
+				return errors.Wrapf(err, "decoding persisted state for job '%s'", job.Name)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, &entry{job: job, schedule: schedule, state: state})
+
+	return nil
+}
+
+// Run blocks, firing due entries on every tick, until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !s.elector.IsLeader(ctx) {
+				continue
+			}
+			s.tick(ctx, now)
+		}
+	}
+}
+
+// tick submits every due, enabled entry and advances its schedule. Errors
+// submitting or persisting one entry don't prevent the rest from running;
+// a stuck queue or store shouldn't take the whole scheduler down with it.
+//
+// The entry list is copied out under s.mu so that Put/SaveState -- both of
+// which can block on a slow queue or store -- don't hold up Register calls
+// or the rest of this tick; each entry's own state is only ever touched
+// from this goroutine, so mutating it afterwards without the lock is safe.
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	entries := make([]*entry, len(s.entries))
+	copy(entries, s.entries)
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		if now.Before(e.state.NextRun) {
+			continue
+		}
+
+		// NextRun is advanced -- and the new state persisted -- regardless
+		// of whether this firing actually submits a job, so a disabled or
+		// failed run still moves the schedule forward instead of firing
+		// repeatedly once it becomes enabled again, and a restart picks up
+		// the same NextRun this process already committed to.
+		e.state.NextRun = e.schedule.Next(now)
+
+		if e.job.Enabled == nil || e.job.Enabled() {
+			if err := s.queue.Put(ctx, e.job.Factory()); err == nil {
+				e.state.LastRun = now
+			}
+		}
+
+		s.persist(ctx, e)
+	}
+}
+
+func (s *Scheduler) persist(ctx context.Context, e *entry) {
+	if s.store == nil {
+		return
+	}
+
+	encoded, err := registry.EncodeInterchangePayload(s.format, e.state)
+	if err != nil {
+		return
+	}
+
+	_ = s.store.SaveState(ctx, e.job.Name, encoded)
+}
@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeJob struct {
+	name string
+	dep  dependency.Manager
+}
+
+func newFakeJob(name string) amboy.Job { return &fakeJob{name: name, dep: dependency.NewAlways()} }
+
+func (j *fakeJob) ID() string                                { return j.name }
+func (j *fakeJob) Run(ctx context.Context)                   {}
+func (j *fakeJob) Type() amboy.JobType                       { return amboy.JobType{Name: "fake", Version: 0} }
+func (j *fakeJob) Dependency() dependency.Manager            { return j.dep }
+func (j *fakeJob) SetDependency(d dependency.Manager)        { j.dep = d }
+func (j *fakeJob) Error() error                              { return nil }
+func (j *fakeJob) AddError(error)                            {}
+func (j *fakeJob) Status() amboy.JobStatusInfo               { return amboy.JobStatusInfo{} }
+func (j *fakeJob) SetStatus(amboy.JobStatusInfo)             {}
+func (j *fakeJob) TimeInfo() amboy.JobTimeInfo               { return amboy.JobTimeInfo{} }
+func (j *fakeJob) UpdateTimeInfo(amboy.JobTimeInfo)          {}
+func (j *fakeJob) RetryInfo() amboy.JobRetryInfo             { return amboy.JobRetryInfo{} }
+func (j *fakeJob) UpdateRetryInfo(amboy.JobRetryInfoOptions) {}
+func (j *fakeJob) Scopes() []string                          { return nil }
+func (j *fakeJob) SetScopes([]string)                        {}
+func (j *fakeJob) EnqueueScopes() []string                   { return nil }
+func (j *fakeJob) SetEnqueueScopes(...string)                {}
+func (j *fakeJob) EnqueueAllScopes() bool                    { return false }
+func (j *fakeJob) SetEnqueueAllScopes(bool)                  {}
+
+type fakeQueue struct {
+	mu   sync.Mutex
+	jobs []amboy.Job
+}
+
+func (q *fakeQueue) Put(ctx context.Context, j amboy.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, j)
+	return nil
+}
+
+func (q *fakeQueue) count() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs)
+}
+
+type memoryStore struct {
+	mu    sync.Mutex
+	state map[string][]byte
+}
+
+func newMemoryStore() *memoryStore { return &memoryStore{state: map[string][]byte{}} }
+
+func (s *memoryStore) SaveState(ctx context.Context, name string, encoded []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state[name] = encoded
+	return nil
+}
+
+func (s *memoryStore) LoadState(ctx context.Context, name string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	encoded, ok := s.state[name]
+	return encoded, ok, nil
+}
+
+type SchedulerSuite struct {
+	queue *fakeQueue
+	store *memoryStore
+	sched *Scheduler
+	suite.Suite
+}
+
+func TestSchedulerSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerSuite))
+}
+
+func (s *SchedulerSuite) SetupTest() {
+	s.queue = &fakeQueue{}
+	s.store = newMemoryStore()
+	s.sched = New(s.queue, s.store, nil)
+}
+
+func (s *SchedulerSuite) TestDueEntryIsSubmittedAndStateIsPersisted() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.sched.Register(ctx, ScheduledJob{
+		Name:    "every-minute",
+		Spec:    "* * * * *",
+		Factory: func() amboy.Job { return newFakeJob("every-minute-job") },
+	}))
+
+	s.sched.entries[0].state.NextRun = time.Now().Add(-time.Second)
+
+	s.sched.tick(ctx, time.Now())
+
+	s.Equal(1, s.queue.count())
+
+	_, found, err := s.store.LoadState(ctx, "every-minute")
+	s.Require().NoError(err)
+	s.True(found)
+}
+
+func (s *SchedulerSuite) TestDisabledEntryIsSkipped() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.sched.Register(ctx, ScheduledJob{
+		Name:    "disabled",
+		Spec:    "* * * * *",
+		Factory: func() amboy.Job { return newFakeJob("disabled-job") },
+		Enabled: func() bool { return false },
+	}))
+
+	s.sched.entries[0].state.NextRun = time.Now().Add(-time.Second)
+
+	s.sched.tick(ctx, time.Now())
+
+	s.Equal(0, s.queue.count())
+}
+
+func (s *SchedulerSuite) TestOfflineAcrossSeveralIntervalsCatchesUpIdempotently() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.sched.Register(ctx, ScheduledJob{
+		Name:    "every-minute",
+		Spec:    "* * * * *",
+		Factory: func() amboy.Job { return newFakeJob("every-minute-job") },
+	}))
+
+	now := time.Now()
+	// Simulate the process having been down for several missed
+	// intervals: NextRun is far enough in the past that, with a
+	// once-a-minute spec, multiple firings were missed.
+	s.sched.entries[0].state.NextRun = now.Add(-5 * time.Minute)
+
+	s.sched.tick(ctx, now)
+
+	s.Equal(1, s.queue.count(), "catch-up should submit exactly one job, not one per missed interval")
+	s.True(s.sched.entries[0].state.NextRun.After(now), "NextRun should advance past now, not to an intermediate missed interval")
+
+	// A second tick at the same time shouldn't resubmit: NextRun already
+	// moved past now on the first tick.
+	s.sched.tick(ctx, now)
+	s.Equal(1, s.queue.count(), "an immediately repeated tick should not resubmit the caught-up entry")
+}
+
+func (s *SchedulerSuite) TestNotYetDueEntryIsSkipped() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.sched.Register(ctx, ScheduledJob{
+		Name:    "future",
+		Spec:    "* * * * *",
+		Factory: func() amboy.Job { return newFakeJob("future-job") },
+	}))
+
+	s.sched.tick(ctx, time.Now())
+
+	s.Equal(0, s.queue.count())
+}